@@ -0,0 +1,220 @@
+package patchclient
+
+import (
+	"container/list"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a single cached GET response along with enough metadata to
+// validate and revalidate it per HTTP caching semantics.
+type CacheEntry struct {
+	Decoded      any
+	ETag         string
+	LastModified string
+	ExpiresAt    time.Time
+
+	// VaryHeaders/VaryValues capture the request header names the
+	// response's Vary header listed, and the values they held when this
+	// entry was stored, so a later request with different values for the
+	// same headers is treated as a miss rather than served stale data.
+	VaryHeaders []string
+	VaryValues  map[string]string
+}
+
+// ResponseCache stores decoded GET responses keyed by method, URL, and the
+// request's Authorization and Account-Type headers (see cacheKeyFor). The
+// default implementation is an in-memory LRU (see NewLRUResponseCache);
+// callers may plug in their own store by implementing this interface.
+type ResponseCache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+	Delete(key string)
+}
+
+// CacheStats is a point-in-time snapshot of cache activity.
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+type statsReporter interface {
+	Stats() CacheStats
+}
+
+// LRUResponseCache is the default ResponseCache: a fixed-capacity,
+// least-recently-used in-memory store.
+type LRUResponseCache struct {
+	capacity int
+
+	mu      sync.Mutex
+	ll      *list.List
+	items   map[string]*list.Element
+	hits    int64
+	misses  int64
+	evicted int64
+}
+
+type lruCacheItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewLRUResponseCache creates an LRUResponseCache holding at most capacity
+// entries.
+func NewLRUResponseCache(capacity int) *LRUResponseCache {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &LRUResponseCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    map[string]*list.Element{},
+	}
+}
+
+func (c *LRUResponseCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return CacheEntry{}, false
+	}
+	c.ll.MoveToFront(elem)
+	c.hits++
+	return elem.Value.(*lruCacheItem).entry, true
+}
+
+func (c *LRUResponseCache) Set(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*lruCacheItem).entry = entry
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	elem := c.ll.PushFront(&lruCacheItem{key: key, entry: entry})
+	c.items[key] = elem
+
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruCacheItem).key)
+			c.evicted++
+		}
+	}
+}
+
+func (c *LRUResponseCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		c.ll.Remove(elem)
+		delete(c.items, key)
+	}
+}
+
+func (c *LRUResponseCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Evictions: c.evicted}
+}
+
+// storeCacheEntry builds a CacheEntry from a successful response and stores
+// it, unless the request carried an Authorization header and the response
+// didn't explicitly opt in via Cache-Control: public.
+func storeCacheEntry(cache ResponseCache, key string, requestHeaders map[string]string, result httpAttemptResult) {
+	if _, hasAuth := requestHeaders["Authorization"]; hasAuth && !cacheControlAllowsAuthorizedCaching(result.header) {
+		return
+	}
+
+	varyHeaders := varyHeaderNames(result.header)
+	cache.Set(key, CacheEntry{
+		Decoded:      result.decoded,
+		ETag:         result.header.Get("ETag"),
+		LastModified: result.header.Get("Last-Modified"),
+		ExpiresAt:    cacheFreshnessFromHeaders(result.header, time.Now()),
+		VaryHeaders:  varyHeaders,
+		VaryValues:   varySnapshot(varyHeaders, requestHeaders),
+	})
+}
+
+func varyMatches(entry CacheEntry, requestHeaders map[string]string) bool {
+	for _, name := range entry.VaryHeaders {
+		if entry.VaryValues[name] != requestHeaders[name] {
+			return false
+		}
+	}
+	return true
+}
+
+func varyHeaderNames(header http.Header) []string {
+	raw := header.Get("Vary")
+	if raw == "" {
+		return nil
+	}
+	var names []string
+	for _, part := range strings.Split(raw, ",") {
+		if name := canonicalHeaderKey(part); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func varySnapshot(names []string, requestHeaders map[string]string) map[string]string {
+	snapshot := make(map[string]string, len(names))
+	for _, name := range names {
+		snapshot[name] = requestHeaders[name]
+	}
+	return snapshot
+}
+
+// cacheFreshnessFromHeaders returns when a response stops being fresh per
+// Cache-Control: max-age (preferred) or Expires. A zero time means the
+// response carried no freshness lifetime and must always be revalidated.
+func cacheFreshnessFromHeaders(header http.Header, now time.Time) time.Time {
+	cc := parseCacheControl(header.Get("Cache-Control"))
+	if v, ok := cc["max-age"]; ok {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return now.Add(time.Duration(secs) * time.Second)
+		}
+	}
+	if exp := header.Get("Expires"); exp != "" {
+		if when, err := http.ParseTime(exp); err == nil {
+			return when
+		}
+	}
+	return time.Time{}
+}
+
+func cacheControlAllowsAuthorizedCaching(header http.Header) bool {
+	_, ok := parseCacheControl(header.Get("Cache-Control"))["public"]
+	return ok
+}
+
+func parseCacheControl(header string) map[string]string {
+	directives := map[string]string{}
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if idx := strings.IndexByte(part, '='); idx >= 0 {
+			key := strings.ToLower(strings.TrimSpace(part[:idx]))
+			directives[key] = strings.Trim(strings.TrimSpace(part[idx+1:]), `"`)
+			continue
+		}
+		directives[strings.ToLower(part)] = ""
+	}
+	return directives
+}
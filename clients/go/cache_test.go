@@ -0,0 +1,331 @@
+package patchclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheServesFreshEntryWithoutNetworkCall(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetResponseCache(NewLRUResponseCache(16))
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+			t.Fatalf("GetPlantList returned error: %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected a single network call, got %d", got)
+	}
+}
+
+func TestResponseCacheRevalidatesStaleETagWith304(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&hits, 1)
+		if n > 1 {
+			if r.Header.Get("If-None-Match") != `"v1"` {
+				t.Errorf("expected conditional request with If-None-Match, got %q", r.Header.Get("If-None-Match"))
+			}
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetResponseCache(NewLRUResponseCache(16))
+
+	first, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("first GetPlantList returned error: %v", err)
+	}
+	second, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("second GetPlantList returned error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Fatalf("expected 2 network calls (initial + conditional), got %d", got)
+	}
+	firstMap, _ := first.(map[string]any)
+	secondMap, _ := second.(map[string]any)
+	if firstMap["ok"] != secondMap["ok"] {
+		t.Fatalf("expected revalidated response to reuse cached body: %v vs %v", first, second)
+	}
+}
+
+func TestResponseCacheSkipsAuthorizedResponsesWithoutPublic(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetAccessToken("secret-token")
+	client.SetResponseCache(NewLRUResponseCache(16))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+			t.Fatalf("GetPlantList returned error: %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Fatalf("expected authorized responses not to be cached, got %d network calls", got)
+	}
+}
+
+func TestResponseCacheCachesAuthorizedResponsesWhenPublic(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetAccessToken("secret-token")
+	client.SetResponseCache(NewLRUResponseCache(16))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+			t.Fatalf("GetPlantList returned error: %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected Cache-Control: public to allow caching, got %d network calls", got)
+	}
+}
+
+func TestResponseCacheTreatsVaryMismatchAsMiss(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Vary", "Accept-Language")
+		w.Header().Set("Cache-Control", "max-age=60")
+		_ = json.NewEncoder(w).Encode(map[string]any{"lang": r.Header.Get("Accept-Language")})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetResponseCache(NewLRUResponseCache(16))
+
+	if _, err := client.GetPlantList(context.Background(), nil, &RequestOptions{Headers: map[string]string{"Accept-Language": "en"}}); err != nil {
+		t.Fatalf("first GetPlantList returned error: %v", err)
+	}
+	if _, err := client.GetPlantList(context.Background(), nil, &RequestOptions{Headers: map[string]string{"Accept-Language": "fr"}}); err != nil {
+		t.Fatalf("second GetPlantList returned error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Fatalf("expected a Vary mismatch to force a fresh request, got %d network calls", got)
+	}
+}
+
+func TestLRUResponseCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewLRUResponseCache(2)
+	cache.Set("a", CacheEntry{Decoded: "a"})
+	cache.Set("b", CacheEntry{Decoded: "b"})
+	cache.Set("c", CacheEntry{Decoded: "c"})
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("expected least-recently-used entry to be evicted")
+	}
+	stats := cache.Stats()
+	if stats.Evictions != 1 {
+		t.Fatalf("expected 1 eviction, got %d", stats.Evictions)
+	}
+}
+
+func TestResponseCacheKeysIncorporateAuthorizationAndAccountType(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		_ = json.NewEncoder(w).Encode(map[string]any{"account": r.Header.Get("Authorization")})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetResponseCache(NewLRUResponseCache(16))
+
+	client.SetAccessToken("tenant-a-token")
+	if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+		t.Fatalf("GetPlantList returned error: %v", err)
+	}
+
+	client.SetAccessToken("tenant-b-token")
+	second, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetPlantList returned error: %v", err)
+	}
+
+	secondMap, _ := second.(map[string]any)
+	if secondMap["account"] != "Bearer tenant-b-token" {
+		t.Fatalf("expected a different tenant's token to miss the cache, got %v", second)
+	}
+}
+
+func TestResponseCacheBypassedWithNoCacheHeader(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetResponseCache(NewLRUResponseCache(16))
+
+	opts := &RequestOptions{Headers: map[string]string{"Cache-Control": "no-cache"}}
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetPlantList(context.Background(), nil, opts); err != nil {
+			t.Fatalf("GetPlantList returned error: %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Fatalf("expected Cache-Control: no-cache to bypass the cache, got %d network calls", got)
+	}
+}
+
+func TestStaleWhileRevalidateServesStaleEntryAndRefreshesInBackground(t *testing.T) {
+	var hits int32
+	refreshed := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if n == 1 {
+			w.Header().Set("Cache-Control", "max-age=0")
+			_ = json.NewEncoder(w).Encode(map[string]any{"hit": 1})
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=60")
+		_ = json.NewEncoder(w).Encode(map[string]any{"hit": 2})
+		close(refreshed)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetResponseCache(NewLRUResponseCache(16))
+	client.SetStaleWhileRevalidate(true)
+
+	first, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("first GetPlantList returned error: %v", err)
+	}
+	firstMap, _ := first.(map[string]any)
+	if firstMap["hit"] != float64(1) {
+		t.Fatalf("unexpected first response: %v", first)
+	}
+
+	second, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("second GetPlantList returned error: %v", err)
+	}
+	secondMap, _ := second.(map[string]any)
+	if secondMap["hit"] != float64(1) {
+		t.Fatalf("expected the stale entry to be served immediately, got %v", second)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a background refresh request")
+	}
+}
+
+func TestStaleWhileRevalidateDedupesConcurrentBackgroundRefreshes(t *testing.T) {
+	var hits int32
+	var refreshesStarted int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if n == 1 {
+			w.Header().Set("Cache-Control", "max-age=0")
+			_ = json.NewEncoder(w).Encode(map[string]any{"hit": 1})
+			return
+		}
+		atomic.AddInt32(&refreshesStarted, 1)
+		<-release
+		w.Header().Set("Cache-Control", "max-age=60")
+		_ = json.NewEncoder(w).Encode(map[string]any{"hit": 2})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetResponseCache(NewLRUResponseCache(16))
+	client.SetStaleWhileRevalidate(true)
+
+	if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+		t.Fatalf("initial GetPlantList returned error: %v", err)
+	}
+
+	const concurrency = 10
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+				t.Errorf("concurrent GetPlantList returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	time.Sleep(50 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&refreshesStarted); got != 1 {
+		t.Fatalf("expected concurrent stale hits to dedupe into a single background refresh, got %d", got)
+	}
+}
+
+func TestClientCacheStatsReportsHitsAndMisses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetResponseCache(NewLRUResponseCache(16))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+			t.Fatalf("GetPlantList returned error: %v", err)
+		}
+	}
+
+	stats := client.CacheStats()
+	if stats.Misses != 1 || stats.Hits != 1 {
+		t.Fatalf("unexpected cache stats: %+v", stats)
+	}
+}
@@ -30,12 +30,22 @@ type RequestOptions struct {
 	AccessToken string
 	AccountType AccountType
 	Headers     map[string]string
+
+	// ProgressFunc, when set, is invoked as bytes are flushed to the wire
+	// during a streaming multipart upload (see FilePart.Reader).
+	ProgressFunc ProgressFunc
 }
 
 type FilePart struct {
 	Filename    string
 	ContentType string
 	Content     []byte
+
+	// Reader, when set, streams the file content directly to the wire via
+	// an io.Pipe instead of buffering it in memory. Size is optional and,
+	// when known, lets the client set Content-Length on the upload request.
+	Reader io.Reader
+	Size   int64
 }
 
 type Client struct {
@@ -46,10 +56,28 @@ type Client struct {
 	AccessToken string
 	AccountType AccountType
 
-	defaultHeaders    map[string]string
-	maxResponseBytes  int64
-	maxMultipartBytes int64
-	allowInsecureHTTP bool
+	defaultHeaders            map[string]string
+	maxResponseBytes          int64
+	maxMultipartBytes         int64
+	allowInsecureHTTP         bool
+	retryPolicy               RetryPolicy
+	rateLimiter               RateLimiter
+	pathRateLimits            []pathRateLimit
+	maxInFlight               chan struct{}
+	middlewares               []Middleware
+	tokenSource               TokenSource
+	tokenSkew                 time.Duration
+	responseCache             ResponseCache
+	staleWhileRevalidate      bool
+	disallowUnknownJSONFields bool
+
+	tokenMu      sync.Mutex
+	cachedToken  string
+	cachedExpiry time.Time
+	refreshing   *tokenRefreshCall
+
+	cacheRefreshMu  sync.Mutex
+	cacheRefreshing map[string]struct{}
 }
 
 type PatchClientError struct {
@@ -57,6 +85,7 @@ type PatchClientError struct {
 	URL        string
 	StatusCode int
 	Body       string
+	Attempts   int
 }
 
 const defaultMaxResponseBytes int64 = 10 << 20
@@ -69,6 +98,9 @@ func (e *PatchClientError) Error() string {
 	if e.Method != "" && e.URL != "" {
 		return fmt.Sprintf("PATCH API request failed: %s %s returned status %d", e.Method, e.URL, e.StatusCode)
 	}
+	if e.StatusCode == 0 && e.Body != "" {
+		return fmt.Sprintf("PATCH API request failed: %s", e.Body)
+	}
 	return fmt.Sprintf("PATCH API request failed with status %d", e.StatusCode)
 }
 
@@ -96,6 +128,7 @@ func NewClient(baseURL string) *Client {
 		defaultHeaders:    map[string]string{},
 		maxResponseBytes:  defaultMaxResponseBytes,
 		maxMultipartBytes: defaultMaxMultipartBytes,
+		rateLimiter:       noopRateLimiter{},
 	}
 }
 
@@ -158,6 +191,221 @@ func (c *Client) SetAllowInsecureHTTP(allow bool) {
 	c.allowInsecureHTTP = allow
 }
 
+// SetRetryPolicy configures automatic retries for requests issued by this
+// client through doJSON, including buffered multipart uploads. Streaming
+// uploads (a FilePart with Reader set) are sent once and never retried. A
+// zero-value RetryPolicy (the default) disables retries.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retryPolicy = policy
+}
+
+func (c *Client) getRetryPolicy() RetryPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.retryPolicy
+}
+
+// SetRateLimit installs a token-bucket RateLimiter keyed per request host,
+// allowing rps requests per second with bursts up to burst. Pass rps <= 0 to
+// remove rate limiting.
+func (c *Client) SetRateLimit(rps float64, burst int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if rps <= 0 {
+		c.rateLimiter = noopRateLimiter{}
+		return
+	}
+	c.rateLimiter = newTokenBucketRateLimiter(rps, burst)
+}
+
+// SetRateLimiter installs a custom RateLimiter, e.g. one wrapping
+// golang.org/x/time/rate or another external limiter. Pass nil to restore
+// the default no-op limiter.
+func (c *Client) SetRateLimiter(limiter RateLimiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if limiter == nil {
+		limiter = noopRateLimiter{}
+	}
+	c.rateLimiter = limiter
+}
+
+func (c *Client) getRateLimiter() RateLimiter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.rateLimiter
+}
+
+// SetRateLimitForPath installs a token-bucket RateLimiter that applies only
+// to requests whose path starts with prefix, overriding the Client's
+// default rate limit for those requests. Calling it again with the same
+// prefix replaces the previous override; pass rps <= 0 to remove it.
+func (c *Client) SetRateLimitForPath(prefix string, rps float64, burst int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	filtered := c.pathRateLimits[:0:0]
+	for _, pr := range c.pathRateLimits {
+		if pr.prefix != prefix {
+			filtered = append(filtered, pr)
+		}
+	}
+	if rps > 0 {
+		filtered = append(filtered, pathRateLimit{prefix: prefix, limiter: newTokenBucketRateLimiter(rps, burst)})
+	}
+	c.pathRateLimits = filtered
+}
+
+// rateLimiterForPath returns the RateLimiter for the longest matching
+// per-path override, falling back to the Client's default rate limiter.
+func (c *Client) rateLimiterForPath(path string) RateLimiter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var best *pathRateLimit
+	for i := range c.pathRateLimits {
+		pr := &c.pathRateLimits[i]
+		if !strings.HasPrefix(path, pr.prefix) {
+			continue
+		}
+		if best == nil || len(pr.prefix) > len(best.prefix) {
+			best = pr
+		}
+	}
+	if best != nil {
+		return best.limiter
+	}
+	return c.rateLimiter
+}
+
+// SetMaxInFlight bounds the number of requests this Client will have
+// outstanding at once via a semaphore that doJSON acquires before
+// dispatching each attempt. Pass n <= 0 to remove the cap (the default).
+func (c *Client) SetMaxInFlight(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n <= 0 {
+		c.maxInFlight = nil
+		return
+	}
+	c.maxInFlight = make(chan struct{}, n)
+}
+
+func (c *Client) getMaxInFlight() chan struct{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.maxInFlight
+}
+
+// acquireSlot blocks until a concurrency slot is available (a no-op if
+// SetMaxInFlight was never called), returning a release func to call once
+// the request completes.
+func (c *Client) acquireSlot(ctx context.Context) (func(), error) {
+	sem := c.getMaxInFlight()
+	if sem == nil {
+		return func() {}, nil
+	}
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// RateLimiterStats reports the active rate limiter's adaptive throttling
+// state for the given path, if it reports stats. It returns a zero
+// RateLimiterStats when the limiter doesn't implement stats reporting.
+func (c *Client) RateLimiterStats(path string) RateLimiterStats {
+	if reporter, ok := c.rateLimiterForPath(path).(rateLimiterStatsReporter); ok {
+		return reporter.Stats()
+	}
+	return RateLimiterStats{}
+}
+
+// SetResponseCache installs a ResponseCache used to short-circuit safe GET
+// requests per HTTP cache-control semantics. Pass nil (the default) to
+// disable response caching.
+func (c *Client) SetResponseCache(cache ResponseCache) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responseCache = cache
+}
+
+func (c *Client) getResponseCache() ResponseCache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.responseCache
+}
+
+// SetStaleWhileRevalidate controls whether a stale cache entry is served
+// immediately while a background request refreshes it (true), or the
+// caller blocks on a conditional revalidation request as usual (false, the
+// default).
+func (c *Client) SetStaleWhileRevalidate(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.staleWhileRevalidate = enabled
+}
+
+func (c *Client) getStaleWhileRevalidate() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.staleWhileRevalidate
+}
+
+// revalidateInBackground re-issues the logical request behind a stale cache
+// hit on a detached context, so a slow or failed refresh can't affect the
+// caller that already got the stale value. doJSON's normal caching logic
+// updates the cache entry once the refresh completes.
+//
+// It dedupes concurrent refreshes by cacheKey: if a refresh for this key is
+// already in flight, additional callers that observe the same stale entry
+// are no-ops instead of each spawning their own redundant request. Unlike
+// tokensource.go's tokenRefreshCall, those callers do not wait for the
+// in-flight refresh.
+func (c *Client) revalidateInBackground(cacheKey, method, path string, query map[string]string, jsonBody any, rawBody []byte, opts *RequestOptions) {
+	c.cacheRefreshMu.Lock()
+	if c.cacheRefreshing == nil {
+		c.cacheRefreshing = make(map[string]struct{})
+	}
+	if _, inFlight := c.cacheRefreshing[cacheKey]; inFlight {
+		c.cacheRefreshMu.Unlock()
+		return
+	}
+	c.cacheRefreshing[cacheKey] = struct{}{}
+	c.cacheRefreshMu.Unlock()
+
+	go func() {
+		defer func() {
+			c.cacheRefreshMu.Lock()
+			delete(c.cacheRefreshing, cacheKey)
+			c.cacheRefreshMu.Unlock()
+		}()
+		_, _ = c.doJSONWithStaleServe(context.Background(), method, path, query, jsonBody, rawBody, opts, false)
+	}()
+}
+
+// cacheKeyFor builds a cache key that incorporates the Authorization and
+// Account-Type headers alongside the method and URL, so responses for one
+// tenant's credentials are never served to a request made with another's.
+func cacheKeyFor(method, target string, headers map[string]string) string {
+	return method + " " + target + "|auth=" + headers["Authorization"] + "|account=" + headers["Account-Type"]
+}
+
+// CacheStats reports hits/misses/evictions for the installed ResponseCache,
+// if it reports statistics. It returns a zero CacheStats when no cache is
+// installed or the installed cache doesn't implement stats reporting.
+func (c *Client) CacheStats() CacheStats {
+	cache := c.getResponseCache()
+	if reporter, ok := cache.(statsReporter); ok {
+		return reporter.Stats()
+	}
+	return CacheStats{}
+}
+
 func (c *Client) AuthenticateUser(ctx context.Context, payload any) (any, error) {
 	return c.doJSON(ctx, http.MethodPost, "/api/v3/account/auth-with-password", nil, payload, nil, nil)
 }
@@ -204,6 +452,11 @@ func (c *Client) UploadPlantFiles(ctx context.Context, plantID string, fields ma
 	if err != nil {
 		return nil, err
 	}
+
+	if usesStreamingParts(normalizedFiles) {
+		return c.uploadPlantFilesStreaming(ctx, path, normalizedFields, normalizedFiles, opts)
+	}
+
 	contentType, payload, err := encodeMultipart(normalizedFields, normalizedFiles, c.multipartLimit())
 	if err != nil {
 		return nil, err
@@ -289,13 +542,31 @@ func (c *Client) doJSON(
 	jsonBody any,
 	rawBody []byte,
 	opts *RequestOptions,
+) (any, error) {
+	return c.doJSONWithStaleServe(ctx, method, path, query, jsonBody, rawBody, opts, true)
+}
+
+// doJSONWithStaleServe is doJSON's implementation, parameterized on whether a
+// stale-while-revalidate cache hit may be served immediately. allowStaleServe
+// is false for the background refresh request revalidateInBackground issues,
+// so that request always reaches the network instead of re-serving the same
+// stale entry and re-triggering another background refresh.
+func (c *Client) doJSONWithStaleServe(
+	ctx context.Context,
+	method string,
+	path string,
+	query map[string]string,
+	jsonBody any,
+	rawBody []byte,
+	opts *RequestOptions,
+	allowStaleServe bool,
 ) (any, error) {
 	target, err := c.buildURL(path, query)
 	if err != nil {
 		return nil, err
 	}
 
-	var body io.Reader
+	var bodyBytes []byte
 	contentType := ""
 	hasBody := false
 	if jsonBody != nil {
@@ -303,19 +574,14 @@ func (c *Client) doJSON(
 		if marshalErr != nil {
 			return nil, marshalErr
 		}
-		body = bytes.NewReader(encoded)
+		bodyBytes = encoded
 		contentType = "application/json"
 		hasBody = true
 	} else if rawBody != nil {
-		body = bytes.NewReader(rawBody)
+		bodyBytes = rawBody
 		hasBody = true
 	}
 
-	req, err := http.NewRequestWithContext(nonNilContext(ctx), method, target, body)
-	if err != nil {
-		return nil, err
-	}
-
 	headers := c.mergeHeaders(opts)
 	if headers["Accept"] == "" {
 		headers["Accept"] = "application/json"
@@ -323,58 +589,223 @@ func (c *Client) doJSON(
 	if contentType != "" {
 		headers["Content-Type"] = contentType
 	}
-
-	for k, v := range headers {
-		req.Header.Set(k, v)
+	if hasBody && headers["Idempotency-Key"] == "" {
+		key, keyErr := newIdempotencyKey()
+		if keyErr != nil {
+			return nil, keyErr
+		}
+		headers["Idempotency-Key"] = key
 	}
 
 	if c.shouldBlockInsecureRequest(target) {
 		return nil, fmt.Errorf("refusing to send request over insecure transport")
 	}
 
+	ctx = nonNilContext(ctx)
+
+	if ts := c.getTokenSource(); ts != nil && (opts == nil || opts.AccessToken == "") {
+		token, tokenErr := c.ensureToken(ctx, ts)
+		if tokenErr != nil {
+			return nil, tokenErr
+		}
+		headers["Authorization"] = asBearer(token)
+	}
+
+	cache := c.getResponseCache()
+	_, noCache := parseCacheControl(headers["Cache-Control"])["no-cache"]
+	cacheable := cache != nil && method == http.MethodGet && !noCache
+	cacheKey := cacheKeyFor(method, target, headers)
+	var cached CacheEntry
+	var haveCached bool
+	if cacheable {
+		if entry, ok := cache.Get(cacheKey); ok && varyMatches(entry, headers) {
+			cached, haveCached = entry, true
+			if time.Now().Before(cached.ExpiresAt) {
+				return cached.Decoded, nil
+			}
+			if allowStaleServe && c.getStaleWhileRevalidate() {
+				c.revalidateInBackground(cacheKey, method, path, query, jsonBody, rawBody, opts)
+				return cached.Decoded, nil
+			}
+			if cached.ETag != "" {
+				headers["If-None-Match"] = cached.ETag
+			}
+			if cached.LastModified != "" {
+				headers["If-Modified-Since"] = cached.LastModified
+			}
+		}
+	}
+
+	policy := c.getRetryPolicy()
+	host := requestHost(target)
+	limiter := c.rateLimiterForPath(path)
+
+	authRetried := false
+	var result httpAttemptResult
+	for attempt := 0; ; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if result.err != nil {
+				return nil, result.err
+			}
+			return nil, ctxErr
+		}
+
+		release, acquireErr := c.acquireSlot(ctx)
+		if acquireErr != nil {
+			return nil, acquireErr
+		}
+		if waitErr := limiter.Wait(ctx, host); waitErr != nil {
+			release()
+			return nil, waitErr
+		}
+
+		result = c.doJSONOnce(ctx, method, target, headers, bodyBytes, hasBody)
+		release()
+
+		if result.statusCode == http.StatusTooManyRequests {
+			if at, ok := limiter.(adaptiveRateLimiter); ok {
+				at.Throttle(defaultThrottleCooldown)
+			}
+		} else if result.err == nil {
+			if at, ok := limiter.(adaptiveRateLimiter); ok {
+				at.Restore()
+			}
+		}
+
+		if result.err == nil && result.statusCode == http.StatusNotModified && haveCached {
+			cached.ExpiresAt = cacheFreshnessFromHeaders(result.header, time.Now())
+			cache.Set(cacheKey, cached)
+			return cached.Decoded, nil
+		}
+
+		if result.err == nil {
+			if cacheable {
+				storeCacheEntry(cache, cacheKey, headers, result)
+			}
+			return result.decoded, nil
+		}
+
+		if patchErr, ok := result.err.(*PatchClientError); ok {
+			patchErr.Attempts = attempt + 1
+
+			if ts := c.getTokenSource(); patchErr.StatusCode == http.StatusUnauthorized && !authRetried && ts != nil {
+				authRetried = true
+				c.invalidateToken()
+				token, tokenErr := c.ensureToken(ctx, ts)
+				if tokenErr == nil {
+					headers["Authorization"] = asBearer(token)
+					attempt--
+					continue
+				}
+			}
+		}
+
+		if attempt >= policy.MaxRetries || !isRetryableResult(policy, result) {
+			return nil, result.err
+		}
+
+		retryAfter, haveRetryAfter := retryAfterFromHeader(result.header)
+		wait := computeBackoff(policy, attempt, retryAfter, haveRetryAfter)
+		if sleepErr := sleepWithContext(ctx, wait); sleepErr != nil {
+			return nil, result.err
+		}
+	}
+}
+
+// httpAttemptResult captures the outcome of a single request attempt so the
+// retry loop in doJSON can inspect the status and headers without holding
+// the (already closed) response body open.
+type httpAttemptResult struct {
+	decoded    any
+	statusCode int
+	header     http.Header
+	err        error
+}
+
+func (c *Client) doJSONOnce(
+	ctx context.Context,
+	method string,
+	target string,
+	headers map[string]string,
+	bodyBytes []byte,
+	hasBody bool,
+) httpAttemptResult {
+	var body io.Reader
+	if hasBody {
+		body = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return httpAttemptResult{err: err}
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
 	client := c.httpClient()
 	if shouldDisableRedirects(headers, hasBody) {
 		client = withRedirectsDisabled(client)
 	} else {
 		client = withRedirectSecurityChecks(client, c.shouldBlockInsecureRequest)
 	}
-	resp, err := client.Do(req)
+	resp, err := c.roundTrip(client, req)
 	if err != nil {
-		return nil, err
+		return httpAttemptResult{err: err}
 	}
 	defer resp.Body.Close()
 
+	return c.decodeHTTPResponse(resp, method, target)
+}
+
+// decodeHTTPResponse reads and decodes a response body, enforcing the
+// configured response size limit. It is shared by the buffered doJSONOnce
+// path and the streaming multipart upload path.
+func (c *Client) decodeHTTPResponse(resp *http.Response, method string, target string) httpAttemptResult {
+	if resp.StatusCode == http.StatusNotModified {
+		_, _, _ = readBodyWithLimit(resp.Body, c.responseLimit())
+		return httpAttemptResult{statusCode: resp.StatusCode, header: resp.Header}
+	}
+
 	limit := c.responseLimit()
 	payload, overflowed, err := readBodyWithLimit(resp.Body, limit)
 	if err != nil {
-		return nil, err
+		return httpAttemptResult{statusCode: resp.StatusCode, header: resp.Header, err: err}
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, &PatchClientError{
-			Method:     method,
-			URL:        target,
-			StatusCode: resp.StatusCode,
-			Body:       string(payload),
+		return httpAttemptResult{
+			statusCode: resp.StatusCode,
+			header:     resp.Header,
+			err: &PatchClientError{
+				Method:     method,
+				URL:        target,
+				StatusCode: resp.StatusCode,
+				Body:       string(payload),
+			},
 		}
 	}
 	if overflowed {
-		return nil, fmt.Errorf("response body exceeds %d bytes", limit)
+		return httpAttemptResult{
+			statusCode: resp.StatusCode,
+			header:     resp.Header,
+			err:        fmt.Errorf("response body exceeds %d bytes", limit),
+		}
 	}
 
 	if len(payload) == 0 {
-		return nil, nil
+		return httpAttemptResult{statusCode: resp.StatusCode, header: resp.Header}
 	}
 
 	if isJSONContentType(resp.Header.Get("Content-Type")) {
 		var out any
 		if err := json.Unmarshal(payload, &out); err != nil {
-			return nil, err
+			return httpAttemptResult{statusCode: resp.StatusCode, header: resp.Header, err: err}
 		}
-		return out, nil
+		return httpAttemptResult{decoded: out, statusCode: resp.StatusCode, header: resp.Header}
 	}
 
-	return string(payload), nil
+	return httpAttemptResult{decoded: string(payload), statusCode: resp.StatusCode, header: resp.Header}
 }
 
 func (c *Client) buildURL(path string, query map[string]string) (string, error) {
@@ -563,6 +994,14 @@ func encodePath(v string) string {
 	return url.PathEscape(v)
 }
 
+func requestHost(target string) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
 func cloneMap(in map[string]string) map[string]string {
 	if len(in) == 0 {
 		return map[string]string{}
@@ -0,0 +1,181 @@
+package patchclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RoundTripFunc performs a single HTTP round trip, mirroring
+// http.RoundTripper.RoundTrip but as a plain function so middlewares can be
+// composed without an intermediate type.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTripFunc to add cross-cutting behavior such as
+// logging, tracing, metrics, caching, or auth refresh.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use registers middlewares that wrap every request this Client sends.
+// Middlewares run in the order they were registered: the first one
+// registered is outermost and sees the request first.
+func (c *Client) Use(mws ...Middleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.middlewares = append(c.middlewares, mws...)
+}
+
+func (c *Client) getMiddlewares() []Middleware {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.middlewares) == 0 {
+		return nil
+	}
+	out := make([]Middleware, len(c.middlewares))
+	copy(out, c.middlewares)
+	return out
+}
+
+// roundTrip sends req through the registered middleware chain, terminating
+// in client.Do.
+func (c *Client) roundTrip(client *http.Client, req *http.Request) (*http.Response, error) {
+	terminal := RoundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return client.Do(r)
+	})
+	return chainMiddlewares(c.getMiddlewares(), terminal)(req)
+}
+
+func chainMiddlewares(mws []Middleware, terminal RoundTripFunc) RoundTripFunc {
+	rt := terminal
+	for i := len(mws) - 1; i >= 0; i-- {
+		rt = mws[i](rt)
+	}
+	return rt
+}
+
+// LoggingMiddleware logs one line per request to w, redacting the
+// Authorization and Cookie headers.
+func LoggingMiddleware(w io.Writer) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			fmt.Fprintf(w, "%s %s status=%d duration=%s headers=[%s] err=%v\n",
+				req.Method, req.URL.String(), status, time.Since(start), redactedHeaders(req.Header), err)
+			return resp, err
+		}
+	}
+}
+
+func redactedHeaders(h http.Header) string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		v := h.Get(k)
+		if strings.EqualFold(k, "Authorization") || strings.EqualFold(k, "Cookie") {
+			v = "REDACTED"
+		}
+		b.WriteString(k + "=" + v)
+	}
+	return b.String()
+}
+
+// Span and Tracer are a minimal tracing seam, not an integration with
+// go.opentelemetry.io/otel: this package has no dependency on it (or any
+// other tracing library) and OTelMiddleware does not produce OTel spans on
+// its own. To get real OTel spans, write a small adapter type implementing
+// Tracer/Span in terms of an actual go.opentelemetry.io/otel.Tracer and
+// trace.Span and pass that adapter to OTelMiddleware.
+type Span interface {
+	SetAttributes(attrs map[string]any)
+	RecordError(err error)
+	End()
+}
+
+type Tracer interface {
+	Start(req *http.Request, name string) (*http.Request, Span)
+}
+
+// OTelMiddleware emits a client span per request with URL, method, status,
+// and error attributes via the supplied Tracer.
+func OTelMiddleware(tracer Tracer) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if tracer == nil {
+				return next(req)
+			}
+
+			tracedReq, span := tracer.Start(req, "patchclient.request")
+			defer span.End()
+			span.SetAttributes(map[string]any{
+				"http.method":    tracedReq.Method,
+				"http.url":       tracedReq.URL.String(),
+				"patch.endpoint": tracedReq.URL.Path,
+			})
+
+			resp, err := next(tracedReq)
+			if err != nil {
+				span.RecordError(err)
+				return resp, err
+			}
+			span.SetAttributes(map[string]any{"http.status_code": resp.StatusCode})
+			return resp, nil
+		}
+	}
+}
+
+// MetricsRecorder is a minimal metrics seam, not an integration with
+// github.com/prometheus/client_golang: this package has no dependency on it
+// (or any other metrics library), and MetricsMiddleware does not publish
+// Prometheus metrics on its own. To get real Prometheus metrics, write a
+// small adapter type implementing MetricsRecorder in terms of an actual
+// prometheus.CounterVec/HistogramVec/GaugeVec and pass that adapter to
+// MetricsMiddleware.
+type MetricsRecorder interface {
+	IncRequests(endpoint string, statusCode int)
+	ObserveLatency(endpoint string, duration time.Duration)
+	IncInFlight(endpoint string)
+	DecInFlight(endpoint string)
+}
+
+// MetricsMiddleware reports request count, latency, and in-flight gauges to
+// recorder, labeled by endpoint (the request URL's path).
+func MetricsMiddleware(recorder MetricsRecorder) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if recorder == nil {
+				return next(req)
+			}
+
+			endpoint := req.URL.Path
+			recorder.IncInFlight(endpoint)
+			defer recorder.DecInFlight(endpoint)
+
+			start := time.Now()
+			resp, err := next(req)
+			recorder.ObserveLatency(endpoint, time.Since(start))
+
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			recorder.IncRequests(endpoint, status)
+			return resp, err
+		}
+	}
+}
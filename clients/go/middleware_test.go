@@ -0,0 +1,190 @@
+package patchclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUseRunsMiddlewaresInRegistrationOrder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	var order []string
+	marker := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next(req)
+			}
+		}
+	}
+
+	client := NewClient(srv.URL)
+	client.Use(marker("outer"), marker("inner"))
+
+	_, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetPlantList returned error: %v", err)
+	}
+
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Fatalf("unexpected middleware execution order: %v", order)
+	}
+}
+
+func TestLoggingMiddlewareRedactsAuthorizationHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(srv.URL)
+	client.SetAccessToken("super-secret")
+	client.Use(LoggingMiddleware(&buf))
+
+	_, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetPlantList returned error: %v", err)
+	}
+
+	logged := buf.String()
+	if strings.Contains(logged, "super-secret") {
+		t.Fatalf("expected Authorization header to be redacted, got: %s", logged)
+	}
+	if !strings.Contains(logged, "Authorization=REDACTED") {
+		t.Fatalf("expected redacted Authorization marker, got: %s", logged)
+	}
+}
+
+type fakeSpan struct {
+	attrs map[string]any
+	err   error
+	ended bool
+}
+
+func (s *fakeSpan) SetAttributes(attrs map[string]any) {
+	for k, v := range attrs {
+		s.attrs[k] = v
+	}
+}
+func (s *fakeSpan) RecordError(err error) { s.err = err }
+func (s *fakeSpan) End()                  { s.ended = true }
+
+type fakeTracer struct {
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(req *http.Request, name string) (*http.Request, Span) {
+	span := &fakeSpan{attrs: map[string]any{}}
+	t.spans = append(t.spans, span)
+	return req, span
+}
+
+func TestOTelMiddlewareRecordsStatusAttribute(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	tracer := &fakeTracer{}
+	client := NewClient(srv.URL)
+	client.Use(OTelMiddleware(tracer))
+
+	_, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetPlantList returned error: %v", err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("expected exactly one span, got %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if !span.ended {
+		t.Fatal("expected span to be ended")
+	}
+	if span.attrs["http.status_code"] != http.StatusOK {
+		t.Fatalf("unexpected status attribute: %v", span.attrs["http.status_code"])
+	}
+	if span.attrs["patch.endpoint"] != "/api/v3/plants" {
+		t.Fatalf("unexpected endpoint attribute: %v", span.attrs["patch.endpoint"])
+	}
+}
+
+type fakeMetricsRecorder struct {
+	mu          sync.Mutex
+	requests    []string
+	statuses    []int
+	latencies   int
+	inFlight    int
+	maxInFlight int
+}
+
+func (r *fakeMetricsRecorder) IncRequests(endpoint string, statusCode int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, endpoint)
+	r.statuses = append(r.statuses, statusCode)
+}
+
+func (r *fakeMetricsRecorder) ObserveLatency(endpoint string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies++
+}
+
+func (r *fakeMetricsRecorder) IncInFlight(endpoint string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.inFlight++
+	if r.inFlight > r.maxInFlight {
+		r.maxInFlight = r.inFlight
+	}
+}
+
+func (r *fakeMetricsRecorder) DecInFlight(endpoint string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.inFlight--
+}
+
+func TestMetricsMiddlewareRecordsRequestsLatencyAndInFlight(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	recorder := &fakeMetricsRecorder{}
+	client := NewClient(srv.URL)
+	client.Use(MetricsMiddleware(recorder))
+
+	_, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetPlantList returned error: %v", err)
+	}
+
+	if len(recorder.requests) != 1 || recorder.requests[0] != "/api/v3/plants" {
+		t.Fatalf("unexpected requests recorded: %v", recorder.requests)
+	}
+	if recorder.statuses[0] != http.StatusOK {
+		t.Fatalf("unexpected status recorded: %d", recorder.statuses[0])
+	}
+	if recorder.latencies != 1 {
+		t.Fatalf("expected 1 latency observation, got %d", recorder.latencies)
+	}
+	if recorder.maxInFlight != 1 {
+		t.Fatalf("expected in-flight gauge to reach 1, got %d", recorder.maxInFlight)
+	}
+	if recorder.inFlight != 0 {
+		t.Fatalf("expected in-flight gauge to return to 0, got %d", recorder.inFlight)
+	}
+}
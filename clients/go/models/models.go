@@ -0,0 +1,43 @@
+// Package models holds typed response shapes for the documented PATCH API
+// endpoints, for callers that want Do[T]/the *Typed client methods instead
+// of decoding into any.
+package models
+
+import "time"
+
+// Plant is a single solar plant as returned by GetPlantDetailsTyped and an
+// element of GetPlantListTyped.
+type Plant struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	Capacity  float64   `json:"capacity"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InverterLog is a single logged reading for an inverter, returned by
+// ListInverterLogsTyped and ListInverterLogsByIDTyped.
+type InverterLog struct {
+	InverterID string    `json:"inverter_id"`
+	Timestamp  time.Time `json:"timestamp"`
+	Status     string    `json:"status"`
+	PowerW     float64   `json:"power_w"`
+}
+
+// DeviceMetric is a single device's latest metric reading, returned by
+// GetLatestDeviceMetricsTyped.
+type DeviceMetric struct {
+	DeviceID  string    `json:"device_id"`
+	Metric    string    `json:"metric"`
+	Value     float64   `json:"value"`
+	Unit      string    `json:"unit"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AccountInfo describes the authenticated account, returned by
+// GetAccountInfoTyped.
+type AccountInfo struct {
+	ID             string `json:"id"`
+	Email          string `json:"email"`
+	OrganizationID string `json:"organization_id"`
+}
@@ -0,0 +1,363 @@
+package patchclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+)
+
+// ProgressFunc is invoked as bytes are flushed to the wire during a
+// streaming multipart upload. total is -1 when the overall payload size
+// could not be determined up front.
+type ProgressFunc func(written, total int64)
+
+// FilePartStream describes a file to upload whose content is read from Body
+// as it is sent rather than buffered in memory first. It is equivalent to a
+// FilePart with Reader and Size set; use it with UploadPlantFilesStream when
+// that is a clearer call site than populating FilePart directly.
+type FilePartStream struct {
+	Filename    string
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
+
+// UploadPlantFilesStream uploads plant files whose content streams from an
+// io.Reader instead of being buffered in memory, so multi-GB files are never
+// held in memory all at once. SetMaxMultipartBytes still caps the total
+// streamed payload, and the upload fails once it is exceeded. It shares the same
+// streaming, progress-reporting, and byte-cap behavior as passing
+// FilePart.Reader to UploadPlantFiles.
+func (c *Client) UploadPlantFilesStream(
+	ctx context.Context,
+	plantID string,
+	fields map[string]string,
+	files map[string]FilePartStream,
+	opts *RequestOptions,
+) (any, error) {
+	converted := make(map[string]FilePart, len(files))
+	for k, f := range files {
+		converted[k] = FilePart{
+			Filename:    f.Filename,
+			ContentType: f.ContentType,
+			Reader:      f.Body,
+			Size:        f.Size,
+		}
+	}
+	return c.UploadPlantFiles(ctx, plantID, fields, converted, opts)
+}
+
+func usesStreamingParts(files map[string]FilePart) bool {
+	for _, f := range files {
+		if f.Reader != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) uploadPlantFilesStreaming(
+	ctx context.Context,
+	path string,
+	fields map[string]string,
+	files map[string]FilePart,
+	opts *RequestOptions,
+) (any, error) {
+	var progress ProgressFunc
+	if opts != nil {
+		progress = opts.ProgressFunc
+	}
+
+	contentType, body, contentLength, err := encodeMultipartStream(fields, files, c.multipartLimit(), progress)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := c.buildURL(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.shouldBlockInsecureRequest(target) {
+		return nil, fmt.Errorf("refusing to send request over insecure transport")
+	}
+
+	headers := c.mergeHeaders(withContentType(opts, contentType))
+	if headers["Accept"] == "" {
+		headers["Accept"] = "application/json"
+	}
+
+	return c.doMultipartStream(ctx, http.MethodPost, path, target, headers, body, contentLength)
+}
+
+// doMultipartStream sends a single, non-retried request whose body is
+// produced incrementally (e.g. by encodeMultipartStream). Streaming parts
+// are backed by a caller-supplied io.Reader that can only be consumed once,
+// so unlike doJSON this does not buffer or replay the body on failure. It
+// still goes through the same concurrency cap, per-path rate limiting, and
+// adaptive 429 throttling as doJSON, just without a retry loop wrapped
+// around them.
+func (c *Client) doMultipartStream(
+	ctx context.Context,
+	method string,
+	path string,
+	target string,
+	headers map[string]string,
+	body io.Reader,
+	contentLength int64,
+) (any, error) {
+	ctx = nonNilContext(ctx)
+
+	limiter := c.rateLimiterForPath(path)
+	release, acquireErr := c.acquireSlot(ctx)
+	if acquireErr != nil {
+		return nil, acquireErr
+	}
+	defer release()
+
+	if waitErr := limiter.Wait(ctx, requestHost(target)); waitErr != nil {
+		return nil, waitErr
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, io.NopCloser(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentLength >= 0 {
+		req.ContentLength = contentLength
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	client := c.httpClient()
+	if shouldDisableRedirects(headers, true) {
+		client = withRedirectsDisabled(client)
+	} else {
+		client = withRedirectSecurityChecks(client, c.shouldBlockInsecureRequest)
+	}
+
+	resp, err := c.roundTrip(client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := c.decodeHTTPResponse(resp, method, target)
+
+	if result.statusCode == http.StatusTooManyRequests {
+		if at, ok := limiter.(adaptiveRateLimiter); ok {
+			at.Throttle(defaultThrottleCooldown)
+		}
+	} else if result.err == nil {
+		if at, ok := limiter.(adaptiveRateLimiter); ok {
+			at.Restore()
+		}
+	}
+
+	return result.decoded, result.err
+}
+
+// encodeMultipartStream builds a multipart body that is written to an
+// io.Pipe on a background goroutine as it is read, so large FileParts backed
+// by an io.Reader never need to be buffered in full. It returns the
+// Content-Type, the pipe's read side, and the total payload size (-1 if it
+// could not be determined because some part's size is unknown).
+func encodeMultipartStream(
+	fields map[string]string,
+	files map[string]FilePart,
+	limit int64,
+	progress ProgressFunc,
+) (string, io.Reader, int64, error) {
+	if limit <= 0 {
+		limit = defaultMaxMultipartBytes
+	}
+
+	pr, pw := io.Pipe()
+	cw := &countingWriter{w: pw, limit: limit}
+	writer := multipart.NewWriter(cw)
+	contentType := writer.FormDataContentType()
+
+	total, knownTotal := multipartContentLength(fields, files, writer.Boundary())
+	if !knownTotal {
+		total = -1
+	}
+	cw.onWrite = func(written int64) {
+		if progress != nil {
+			progress(written, total)
+		}
+	}
+
+	go func() {
+		err := writeMultipartStreamParts(writer, fields, files)
+		closeErr := writer.Close()
+		if err == nil {
+			err = closeErr
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return contentType, pr, total, nil
+}
+
+func writeMultipartStreamParts(writer *multipart.Writer, fields map[string]string, files map[string]FilePart) error {
+	for _, k := range sortedHeaderKeys(fields) {
+		safeName, err := rejectCRLF(k, "multipart field name")
+		if err != nil {
+			return err
+		}
+		if err := writer.WriteField(safeName, fields[k]); err != nil {
+			return err
+		}
+	}
+
+	for _, fieldName := range sortedFilePartKeys(files) {
+		filePart := files[fieldName]
+		safeFieldName, err := rejectCRLF(fieldName, "multipart file field name")
+		if err != nil {
+			return err
+		}
+		safeFilename, err := rejectCRLF(filePart.Filename, "multipart filename")
+		if err != nil {
+			return err
+		}
+		contentType := filePart.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		safeContentType, err := rejectCRLF(contentType, "multipart content type")
+		if err != nil {
+			return err
+		}
+
+		header := multipartFileHeader(safeFieldName, safeFilename, safeContentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return err
+		}
+
+		if filePart.Reader != nil {
+			if _, err := io.Copy(part, filePart.Reader); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := part.Write(filePart.Content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// countingWriter wraps an io.Writer, rejecting writes once limit bytes have
+// been written and reporting cumulative progress after each successful
+// write.
+type countingWriter struct {
+	w       io.Writer
+	written int64
+	limit   int64
+	onWrite func(written int64)
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.limit > 0 && cw.written+int64(len(p)) > cw.limit {
+		return 0, fmt.Errorf("multipart payload exceeds %d bytes", cw.limit)
+	}
+	n, err := cw.w.Write(p)
+	cw.written += int64(n)
+	if cw.onWrite != nil {
+		cw.onWrite(cw.written)
+	}
+	return n, err
+}
+
+// multipartContentLength computes the exact encoded size of fields and files
+// when every file part has a known size (buffered Content or a Reader with
+// Size set), so the caller can set Content-Length instead of streaming
+// chunked. It mirrors the byte layout multipart.Writer itself produces.
+func multipartContentLength(fields map[string]string, files map[string]FilePart, boundary string) (int64, bool) {
+	var total int64
+	for _, k := range sortedHeaderKeys(fields) {
+		header := multipartFieldHeader(k)
+		total += int64(len("--"+boundary+"\r\n")) + headerByteLen(header) + int64(len(fields[k])) + int64(len("\r\n"))
+	}
+
+	for _, fieldName := range sortedFilePartKeys(files) {
+		filePart := files[fieldName]
+		size, ok := filePartSize(filePart)
+		if !ok {
+			return 0, false
+		}
+		contentType := filePart.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := multipartFileHeader(fieldName, filePart.Filename, contentType)
+		total += int64(len("--"+boundary+"\r\n")) + headerByteLen(header) + size + int64(len("\r\n"))
+	}
+
+	total += int64(len("--" + boundary + "--\r\n"))
+	return total, true
+}
+
+func filePartSize(part FilePart) (int64, bool) {
+	if part.Reader != nil {
+		if part.Size > 0 {
+			return part.Size, true
+		}
+		return 0, false
+	}
+	return int64(len(part.Content)), true
+}
+
+func sortedFilePartKeys(files map[string]FilePart) []string {
+	keys := make([]string, 0, len(files))
+	for k := range files {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func multipartFieldHeader(name string) map[string][]string {
+	return map[string][]string{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(name))},
+	}
+}
+
+func multipartFileHeader(fieldName, filename, contentType string) map[string][]string {
+	return map[string][]string{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(fieldName), escapeQuotes(filename))},
+		"Content-Type":        {contentType},
+	}
+}
+
+// headerByteLen returns the encoded byte length of a MIME header followed by
+// the blank line that separates it from the part body, matching how
+// net/textproto writes a MIMEHeader.
+func headerByteLen(header map[string][]string) int64 {
+	var n int64
+	for _, k := range headerKeysInWriteOrder(header) {
+		for _, v := range header[k] {
+			n += int64(len(k)) + int64(len(": ")) + int64(len(v)) + int64(len("\r\n"))
+		}
+	}
+	n += int64(len("\r\n"))
+	return n
+}
+
+// headerKeysInWriteOrder matches multipart.Writer.CreatePart's fixed
+// ordering of the two headers it ever sets.
+func headerKeysInWriteOrder(header map[string][]string) []string {
+	order := []string{"Content-Disposition", "Content-Type"}
+	keys := make([]string, 0, len(order))
+	for _, k := range order {
+		if _, ok := header[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,163 @@
+package patchclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUploadPlantFilesStreamsReaderBackedPartsWithoutBuffering(t *testing.T) {
+	var gotName string
+	var gotBody string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+			t.Errorf("unexpected content type: %s", r.Header.Get("Content-Type"))
+		}
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			if part.FileName() == "" {
+				continue
+			}
+			gotName = part.FileName()
+			body, _ := io.ReadAll(part)
+			gotBody = string(body)
+			break
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	content := "streamed payload"
+	_, err := client.UploadPlantFiles(
+		context.Background(),
+		"plant-1",
+		nil,
+		map[string]FilePart{
+			"filename": {
+				Filename:    "stream.txt",
+				ContentType: "text/plain",
+				Reader:      strings.NewReader(content),
+				Size:        int64(len(content)),
+			},
+		},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("UploadPlantFiles returned error: %v", err)
+	}
+	if gotName != "stream.txt" {
+		t.Fatalf("unexpected filename: %s", gotName)
+	}
+	if gotBody != content {
+		t.Fatalf("unexpected body: %q", gotBody)
+	}
+}
+
+func TestUploadPlantFilesStreamingReportsProgress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	content := strings.Repeat("a", 1024)
+
+	var lastWritten, lastTotal int64
+	calls := 0
+	_, err := client.UploadPlantFiles(
+		context.Background(),
+		"plant-1",
+		nil,
+		map[string]FilePart{
+			"filename": {
+				Filename: "big.txt",
+				Reader:   strings.NewReader(content),
+				Size:     int64(len(content)),
+			},
+		},
+		&RequestOptions{ProgressFunc: func(written, total int64) {
+			calls++
+			lastWritten = written
+			lastTotal = total
+		}},
+	)
+	if err != nil {
+		t.Fatalf("UploadPlantFiles returned error: %v", err)
+	}
+	if calls == 0 {
+		t.Fatal("expected ProgressFunc to be invoked at least once")
+	}
+	if lastWritten != lastTotal {
+		t.Fatalf("expected final progress call to report written == total, got written=%d total=%d", lastWritten, lastTotal)
+	}
+}
+
+func TestUploadPlantFilesStreamingRejectsPayloadAboveConfiguredLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetMaxMultipartBytes(16)
+
+	_, err := client.UploadPlantFiles(
+		context.Background(),
+		"plant-1",
+		nil,
+		map[string]FilePart{
+			"filename": {
+				Filename: "big.txt",
+				Reader:   strings.NewReader(strings.Repeat("a", 1024)),
+				Size:     1024,
+			},
+		},
+		nil,
+	)
+	if err == nil {
+		t.Fatal("expected multipart size limit error, got nil")
+	}
+	if !strings.Contains(err.Error(), "multipart payload exceeds") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMultipartContentLengthKnownWhenAllSizesAreSet(t *testing.T) {
+	fields := map[string]string{"name": "file.txt"}
+	files := map[string]FilePart{
+		"filename": {Filename: "file.txt", ContentType: "text/plain", Content: []byte("hello")},
+	}
+
+	total, ok := multipartContentLength(fields, files, "boundary123")
+	if !ok {
+		t.Fatal("expected content length to be known")
+	}
+	if total <= 0 {
+		t.Fatalf("expected positive content length, got %d", total)
+	}
+}
+
+func TestMultipartContentLengthUnknownWithoutReaderSize(t *testing.T) {
+	files := map[string]FilePart{
+		"filename": {Filename: "file.txt", Reader: strings.NewReader("hello")},
+	}
+
+	_, ok := multipartContentLength(nil, files, "boundary123")
+	if ok {
+		t.Fatal("expected content length to be unknown when Reader has no Size")
+	}
+}
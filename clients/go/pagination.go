@@ -0,0 +1,252 @@
+package patchclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"patchclient/models"
+)
+
+// PageEnvelope is what a PageExtractor pulls out of one decoded response
+// page: the page's items plus enough information to know whether (and how)
+// to fetch the next one.
+type PageEnvelope[T any] struct {
+	Items      []T
+	NextCursor string
+	HasMore    bool
+}
+
+// PageExtractor converts one decoded JSON response page into a
+// PageEnvelope[T]. Response envelope shapes differ across endpoints (e.g.
+// {"items":[...],"cursor":"..."} vs {"logs":[...],"cursor":"..."}), so each
+// iterator constructor supplies the one that matches its endpoint; callers
+// building their own iterator via NewIterator can supply their own.
+type PageExtractor[T any] func(decoded any) (PageEnvelope[T], error)
+
+// extractPage is the shared implementation behind this package's default
+// PageExtractors: it reads itemsKey as a JSON array of T and "cursor" as the
+// next page's cursor, treating a non-empty cursor as "more pages remain".
+func extractPage[T any](decoded any, itemsKey string) (PageEnvelope[T], error) {
+	envelope, ok := decoded.(map[string]any)
+	if !ok {
+		return PageEnvelope[T]{}, fmt.Errorf("pagination: expected a JSON object envelope, got %T", decoded)
+	}
+
+	raw, err := json.Marshal(envelope[itemsKey])
+	if err != nil {
+		return PageEnvelope[T]{}, err
+	}
+	var items []T
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return PageEnvelope[T]{}, err
+		}
+	}
+
+	cursor, _ := envelope["cursor"].(string)
+	return PageEnvelope[T]{Items: items, NextCursor: cursor, HasMore: cursor != ""}, nil
+}
+
+// DefaultPlantPageExtractor assumes GetPlantList returns pages shaped like
+// {"items": [...plant...], "cursor": "..."}.
+func DefaultPlantPageExtractor(decoded any) (PageEnvelope[models.Plant], error) {
+	return extractPage[models.Plant](decoded, "items")
+}
+
+// DefaultInverterLogPageExtractor assumes ListInverterLogs/
+// ListInverterLogsByID return pages shaped like
+// {"logs": [...inverter log...], "cursor": "..."}.
+func DefaultInverterLogPageExtractor(decoded any) (PageEnvelope[models.InverterLog], error) {
+	return extractPage[models.InverterLog](decoded, "logs")
+}
+
+// Iterator lazily fetches successive pages of T via fetch, using extractor
+// to pull items and the next cursor out of each decoded page, until the
+// API reports no further cursor, maxResults items have been yielded (when
+// maxResults > 0), or ctx is done.
+type Iterator[T any] struct {
+	fetch      func(ctx context.Context, cursor string) (any, error)
+	extractor  PageExtractor[T]
+	maxResults int
+
+	buf     []T
+	cursor  string
+	started bool
+	done    bool
+	fetched int
+	lastErr error
+}
+
+// NewIterator builds an Iterator around a caller-supplied fetch function and
+// PageExtractor, for endpoints beyond this package's built-in constructors.
+// Pass maxResults <= 0 for no cap.
+func NewIterator[T any](fetch func(ctx context.Context, cursor string) (any, error), extractor PageExtractor[T], maxResults int) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch, extractor: extractor, maxResults: maxResults}
+}
+
+// Next returns the next item, or ok == false once the iterator is
+// exhausted. A non-nil error means the underlying request or page
+// extraction failed; the iterator is done after that regardless of ok.
+func (it *Iterator[T]) Next(ctx context.Context) (T, bool, error) {
+	var zero T
+	for len(it.buf) == 0 {
+		if it.done {
+			return zero, false, nil
+		}
+		if it.maxResults > 0 && it.fetched >= it.maxResults {
+			it.done = true
+			return zero, false, nil
+		}
+		if it.started && it.cursor == "" {
+			it.done = true
+			return zero, false, nil
+		}
+		it.started = true
+
+		decoded, err := it.fetch(ctx, it.cursor)
+		if err != nil {
+			it.done = true
+			return zero, false, err
+		}
+		page, err := it.extractor(decoded)
+		if err != nil {
+			it.done = true
+			return zero, false, err
+		}
+
+		it.buf = page.Items
+		if page.HasMore && page.NextCursor != "" {
+			it.cursor = page.NextCursor
+		} else {
+			it.cursor = ""
+		}
+		if len(it.buf) == 0 && it.cursor == "" {
+			it.done = true
+		}
+	}
+
+	item := it.buf[0]
+	it.buf = it.buf[1:]
+	it.fetched++
+	if it.maxResults > 0 && it.fetched >= it.maxResults {
+		it.buf = nil
+		it.cursor = ""
+		it.done = true
+	}
+	return item, true, nil
+}
+
+// IterateAll calls fn for every remaining item, stopping at the first error
+// returned by fn or by the iterator itself.
+func (it *Iterator[T]) IterateAll(ctx context.Context, fn func(T) error) error {
+	for {
+		item, ok, err := it.Next(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+}
+
+// Channel returns a channel of items, closed once the iterator is exhausted
+// or ctx is done. It's this module's substitute for an iter.Seq2[T, error]
+// range-over-func, which needs Go 1.23; once the minimum Go version here
+// reaches 1.23, an All() method returning iter.Seq2[T, error] can be added
+// alongside Channel without removing it. Check Err() after the channel
+// closes to see whether iteration stopped early due to an error.
+func (it *Iterator[T]) Channel(ctx context.Context) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		for {
+			item, ok, err := it.Next(ctx)
+			if err != nil {
+				it.lastErr = err
+				return
+			}
+			if !ok {
+				return
+			}
+			select {
+			case ch <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Err returns the error that stopped a Channel-driven iteration early, if
+// any. Only meaningful after the channel returned by Channel is closed.
+func (it *Iterator[T]) Err() error {
+	return it.lastErr
+}
+
+// PlantIterator pages through GetPlantList results.
+type PlantIterator = Iterator[models.Plant]
+
+// InverterLogIterator pages through ListInverterLogs/ListInverterLogsByID
+// results.
+type InverterLogIterator = Iterator[models.InverterLog]
+
+// NewPlantIterator pages through GetPlantList, fetching pageSize items per
+// request (when > 0) and stopping after maxResults items (when > 0).
+func (c *Client) NewPlantIterator(query map[string]string, opts *RequestOptions, pageSize int, maxResults int) *PlantIterator {
+	base := cloneMap(query)
+	if pageSize > 0 {
+		base["page_size"] = strconv.Itoa(pageSize)
+	}
+
+	fetch := func(ctx context.Context, cursor string) (any, error) {
+		page := cloneMap(base)
+		if cursor != "" {
+			page["cursor"] = cursor
+		}
+		return c.doJSON(ctx, http.MethodGet, "/api/v3/plants", page, nil, nil, opts)
+	}
+	return NewIterator[models.Plant](fetch, DefaultPlantPageExtractor, maxResults)
+}
+
+// NewInverterLogIterator pages through ListInverterLogs for plantID.
+func (c *Client) NewInverterLogIterator(plantID string, query map[string]string, opts *RequestOptions, pageSize int, maxResults int) *InverterLogIterator {
+	base := cloneMap(query)
+	if pageSize > 0 {
+		base["page_size"] = strconv.Itoa(pageSize)
+	}
+
+	fetch := func(ctx context.Context, cursor string) (any, error) {
+		page := cloneMap(base)
+		if cursor != "" {
+			page["cursor"] = cursor
+		}
+		return c.ListInverterLogs(ctx, plantID, page, opts)
+	}
+	return NewIterator[models.InverterLog](fetch, DefaultInverterLogPageExtractor, maxResults)
+}
+
+// NewInverterLogIteratorByID pages through ListInverterLogsByID for
+// plantID/inverterID.
+func (c *Client) NewInverterLogIteratorByID(plantID string, inverterID string, query map[string]string, opts *RequestOptions, pageSize int, maxResults int) *InverterLogIterator {
+	base := cloneMap(query)
+	if pageSize > 0 {
+		base["page_size"] = strconv.Itoa(pageSize)
+	}
+
+	fetch := func(ctx context.Context, cursor string) (any, error) {
+		page := cloneMap(base)
+		if cursor != "" {
+			page["cursor"] = cursor
+		}
+		return c.ListInverterLogsByID(ctx, plantID, inverterID, page, opts)
+	}
+	return NewIterator[models.InverterLog](fetch, DefaultInverterLogPageExtractor, maxResults)
+}
@@ -0,0 +1,115 @@
+package patchclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"patchclient/models"
+)
+
+func TestPlantIteratorPagesUntilCursorExhausted(t *testing.T) {
+	var cursors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursors = append(cursors, r.URL.Query().Get("cursor"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			_, _ = w.Write([]byte(`{"items":[{"id":"p1"},{"id":"p2"}],"cursor":"page2"}`))
+		case "page2":
+			_, _ = w.Write([]byte(`{"items":[{"id":"p3"}],"cursor":""}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	it := client.NewPlantIterator(nil, nil, 0, 0)
+
+	var ids []string
+	for {
+		plant, ok, err := it.Next(context.Background())
+		if err != nil {
+			t.Fatalf("Next returned error: %v", err)
+		}
+		if !ok {
+			break
+		}
+		ids = append(ids, plant.ID)
+	}
+
+	if len(ids) != 3 || ids[0] != "p1" || ids[1] != "p2" || ids[2] != "p3" {
+		t.Fatalf("unexpected plant IDs: %v", ids)
+	}
+	if len(cursors) != 2 {
+		t.Fatalf("expected 2 page requests, got %d", len(cursors))
+	}
+}
+
+func TestIteratorStopsAtMaxResults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[{"id":"p1"},{"id":"p2"},{"id":"p3"}],"cursor":"more"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	it := client.NewPlantIterator(nil, nil, 0, 2)
+
+	var count int
+	if err := it.IterateAll(context.Background(), func(p models.Plant) error {
+		count++
+		return nil
+	}); err != nil {
+		t.Fatalf("IterateAll returned error: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected maxResults to cap iteration at 2, got %d", count)
+	}
+}
+
+func TestIteratorChannelYieldsAllItems(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			_, _ = w.Write([]byte(`{"items":[{"id":"p1"}],"cursor":"page2"}`))
+		case "page2":
+			_, _ = w.Write([]byte(`{"items":[{"id":"p2"}],"cursor":""}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	it := client.NewPlantIterator(nil, nil, 0, 0)
+
+	var ids []string
+	for plant := range it.Channel(context.Background()) {
+		ids = append(ids, plant.ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Channel iteration returned error: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "p1" || ids[1] != "p2" {
+		t.Fatalf("unexpected plant IDs from Channel: %v", ids)
+	}
+}
+
+func TestInverterLogIteratorUsesLogsEnvelopeKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"logs":[{"inverter_id":"inv-1"}],"cursor":""}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	it := client.NewInverterLogIterator("plant-1", nil, nil, 0, 0)
+
+	log, ok, err := it.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next returned error: %v", err)
+	}
+	if !ok || log.InverterID != "inv-1" {
+		t.Fatalf("unexpected inverter log: %+v (ok=%v)", log, ok)
+	}
+}
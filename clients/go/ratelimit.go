@@ -0,0 +1,177 @@
+package patchclient
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter paces outgoing requests. Implementations must be safe for
+// concurrent use; Wait should return promptly with an error once ctx is
+// done rather than blocking indefinitely. The built-in token-bucket limiter
+// returns a *PatchClientError in that case.
+type RateLimiter interface {
+	Wait(ctx context.Context, host string) error
+}
+
+// noopRateLimiter never blocks. It is the default for a new Client.
+type noopRateLimiter struct{}
+
+func (noopRateLimiter) Wait(ctx context.Context, host string) error {
+	return nil
+}
+
+// tokenBucketRateLimiter enforces an independent token bucket per host, so a
+// single Client reused across multiple base URLs cannot starve one host by
+// hammering another.
+type tokenBucketRateLimiter struct {
+	rps   float64
+	burst int
+
+	mu             sync.Mutex
+	buckets        map[string]*hostBucket
+	throttleFactor float64
+	throttleUntil  time.Time
+}
+
+type hostBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTokenBucketRateLimiter(rps float64, burst int) *tokenBucketRateLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &tokenBucketRateLimiter{
+		rps:            rps,
+		burst:          burst,
+		buckets:        map[string]*hostBucket{},
+		throttleFactor: 1,
+	}
+}
+
+func (l *tokenBucketRateLimiter) Wait(ctx context.Context, host string) error {
+	for {
+		wait, ok := l.reserve(host)
+		if ok {
+			return nil
+		}
+		if err := sleepWithContext(ctx, wait); err != nil {
+			return &PatchClientError{
+				Body: fmt.Sprintf("rate limit wait for host %q cancelled: %v", host, err),
+			}
+		}
+	}
+}
+
+func (l *tokenBucketRateLimiter) reserve(host string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	rps := l.effectiveRPS(now)
+
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &hostBucket{tokens: float64(l.burst), lastRefill: now}
+		l.buckets[host] = b
+	} else if rps > 0 {
+		elapsed := now.Sub(b.lastRefill).Seconds()
+		b.tokens = math.Min(float64(l.burst), b.tokens+elapsed*rps)
+	}
+	b.lastRefill = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	if rps <= 0 {
+		return 0, true
+	}
+
+	deficit := 1 - b.tokens
+	wait := time.Duration(deficit / rps * float64(time.Second))
+	return wait, false
+}
+
+// effectiveRPS returns l.rps, scaled down by the active throttle factor if a
+// cooldown window from a recent 429 is still in effect. Callers must hold
+// l.mu.
+func (l *tokenBucketRateLimiter) effectiveRPS(now time.Time) float64 {
+	if l.throttleUntil.IsZero() || now.After(l.throttleUntil) {
+		l.throttleFactor = 1
+		return l.rps
+	}
+	return l.rps * l.throttleFactor
+}
+
+// defaultThrottleFactor is how much a 429 response shrinks the effective
+// rate for the cooldown window that follows.
+const defaultThrottleFactor = 0.5
+
+// Throttle shrinks the effective rate to a fraction of its configured value
+// for the given cooldown window, e.g. after observing a 429 response.
+func (l *tokenBucketRateLimiter) Throttle(cooldown time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.throttleFactor = defaultThrottleFactor
+	l.throttleUntil = time.Now().Add(cooldown)
+}
+
+// Restore cancels any active throttle cooldown immediately, e.g. after a
+// subsequent request succeeds.
+func (l *tokenBucketRateLimiter) Restore() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.throttleFactor = 1
+	l.throttleUntil = time.Time{}
+}
+
+// RateLimiterStats is a point-in-time snapshot of a rate limiter's adaptive
+// throttling state.
+type RateLimiterStats struct {
+	Throttled    bool
+	EffectiveRPS float64
+}
+
+func (l *tokenBucketRateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	rps := l.effectiveRPS(now)
+	return RateLimiterStats{
+		Throttled:    rps < l.rps,
+		EffectiveRPS: rps,
+	}
+}
+
+// adaptiveRateLimiter is implemented by RateLimiters that support shrinking
+// their rate for a cooldown window in response to 429s. Custom RateLimiters
+// installed via SetRateLimiter need not implement it; doJSON simply skips
+// adaptive throttling when they don't.
+type adaptiveRateLimiter interface {
+	Throttle(cooldown time.Duration)
+	Restore()
+}
+
+// rateLimiterStatsReporter is implemented by RateLimiters that can report
+// RateLimiterStats, mirroring the statsReporter pattern used by ResponseCache.
+type rateLimiterStatsReporter interface {
+	Stats() RateLimiterStats
+}
+
+// defaultThrottleCooldown is how long an adaptive rate limiter stays
+// throttled after a 429 before its rate automatically recovers, absent an
+// earlier successful request restoring it.
+const defaultThrottleCooldown = 30 * time.Second
+
+// pathRateLimit is a per-path-prefix rate override, checked against the
+// longest matching prefix before falling back to the Client's default
+// RateLimiter.
+type pathRateLimit struct {
+	prefix  string
+	limiter *tokenBucketRateLimiter
+}
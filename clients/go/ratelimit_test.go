@@ -0,0 +1,177 @@
+package patchclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetRateLimitThrottlesRequestsToSameHost(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetRateLimit(2, 1)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+			t.Fatalf("GetPlantList returned error: %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	if elapsed < time.Second {
+		t.Fatalf("expected rate limiting to stretch 3 requests at 2rps/burst1 over >=1s, took %s", elapsed)
+	}
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Fatalf("expected all 3 requests to eventually land, got %d", got)
+	}
+}
+
+func TestSetRateLimitAllowsBurstWithoutWaiting(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetRateLimit(1, 5)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+			t.Fatalf("GetPlantList returned error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected burst of 5 to pass immediately, took %s", elapsed)
+	}
+}
+
+func TestRateLimitWaitReturnsPatchClientErrorOnContextExpiry(t *testing.T) {
+	limiter := newTokenBucketRateLimiter(1, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Consume the single burst token so the next Wait call has to block.
+	if err := limiter.Wait(context.Background(), "example.com"); err != nil {
+		t.Fatalf("unexpected error consuming burst token: %v", err)
+	}
+
+	err := limiter.Wait(ctx, "example.com")
+	if err == nil {
+		t.Fatal("expected error when context expires while waiting, got nil")
+	}
+	if _, ok := err.(*PatchClientError); !ok {
+		t.Fatalf("expected *PatchClientError, got %T", err)
+	}
+}
+
+func TestSetRateLimitZeroRPSDisablesLimiting(t *testing.T) {
+	client := NewClient("https://example.com")
+	client.SetRateLimit(5, 5)
+	client.SetRateLimit(0, 0)
+
+	if _, ok := client.getRateLimiter().(noopRateLimiter); !ok {
+		t.Fatalf("expected rps<=0 to restore the no-op limiter, got %T", client.getRateLimiter())
+	}
+}
+
+func TestSetRateLimitForPathOverridesDefaultForMatchingRequests(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetRateLimit(100, 100)
+	client.SetRateLimitForPath("/api/v3/plants", 2, 1)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+			t.Fatalf("GetPlantList returned error: %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	if elapsed < time.Second {
+		t.Fatalf("expected the per-path override to throttle matching requests, took %s", elapsed)
+	}
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Fatalf("expected all 3 requests to eventually land, got %d", got)
+	}
+}
+
+func TestSetMaxInFlightBoundsConcurrentRequests(t *testing.T) {
+	var inFlight, maxSeen int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			seen := atomic.LoadInt32(&maxSeen)
+			if n <= seen || atomic.CompareAndSwapInt32(&maxSeen, seen, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetMaxInFlight(2)
+
+	done := make(chan error, 6)
+	for i := 0; i < 6; i++ {
+		go func() {
+			_, err := client.GetPlantList(context.Background(), nil, nil)
+			done <- err
+		}()
+	}
+	for i := 0; i < 6; i++ {
+		if err := <-done; err != nil {
+			t.Fatalf("GetPlantList returned error: %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&maxSeen); got > 2 {
+		t.Fatalf("expected at most 2 concurrent requests, saw %d", got)
+	}
+}
+
+func TestDoJSONThrottlesAdaptivelyOn429ThenRestoresOnSuccess(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetRateLimit(100, 100)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond})
+
+	if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+		t.Fatalf("GetPlantList returned error: %v", err)
+	}
+
+	stats := client.RateLimiterStats("/api/v3/plants")
+	if stats.Throttled {
+		t.Fatalf("expected the subsequent success to restore the rate limiter, got %+v", stats)
+	}
+}
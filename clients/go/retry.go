@@ -0,0 +1,146 @@
+package patchclient
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math"
+	mathrand "math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy configures automatic retries for requests issued by a Client.
+// A zero-value RetryPolicy disables retries (MaxRetries defaults to 0).
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	Jitter     bool
+
+	// RetryOn, when set, overrides the default retryable-status logic
+	// (network errors, request timeouts, 429, and 5xx other than 501).
+	// It receives the attempt's HTTP status code (0 for a transport-level
+	// error) and the resulting error.
+	RetryOn func(statusCode int, err error) bool
+}
+
+const (
+	defaultRetryBaseDelay = 200 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// isRetryableResult reports whether an attempt result should be retried. It
+// defers to policy.RetryOn when set; otherwise it retries on network
+// errors, request timeouts, 429, and 5xx responses other than 501 (Not
+// Implemented, which will never succeed on retry).
+func isRetryableResult(policy RetryPolicy, result httpAttemptResult) bool {
+	if policy.RetryOn != nil {
+		return policy.RetryOn(result.statusCode, result.err)
+	}
+	if patchErr, ok := result.err.(*PatchClientError); ok {
+		return isRetryableStatus(patchErr.StatusCode)
+	}
+	return result.err != nil
+}
+
+func isRetryableStatus(status int) bool {
+	if status == http.StatusRequestTimeout || status == http.StatusTooManyRequests {
+		return true
+	}
+	return status >= 500 && status < 600 && status != http.StatusNotImplemented
+}
+
+// computeBackoff returns how long to wait before the next attempt. When the
+// server supplied a Retry-After value it takes precedence over the computed
+// exponential delay, capped at policy.MaxDelay.
+func computeBackoff(policy RetryPolicy, attempt int, retryAfter time.Duration, haveRetryAfter bool) time.Duration {
+	maxDelay := policy.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	if haveRetryAfter {
+		if retryAfter < 0 {
+			return 0
+		}
+		if retryAfter > maxDelay {
+			return maxDelay
+		}
+		return retryAfter
+	}
+
+	base := policy.BaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+
+	delay := time.Duration(float64(base) * math.Pow(2, float64(attempt)))
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	if policy.Jitter && delay > 0 {
+		delay = time.Duration(mathrand.Int63n(int64(delay) + 1))
+	}
+	return delay
+}
+
+// newIdempotencyKey generates a random UUIDv4 for the Idempotency-Key
+// header, so a body-bearing request can be safely retried without the
+// server applying it twice.
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// retryAfterFromHeader parses the Retry-After header, supporting both
+// delta-seconds ("120") and HTTP-date forms.
+func retryAfterFromHeader(header http.Header) (time.Duration, bool) {
+	if header == nil {
+		return 0, false
+	}
+	return parseRetryAfter(header.Get("Retry-After"), time.Now())
+}
+
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		return when.Sub(now), true
+	}
+	return 0, false
+}
+
+// sleepWithContext waits for d, returning early with ctx.Err() if ctx is
+// cancelled first.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			return nil
+		}
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
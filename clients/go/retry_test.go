@@ -0,0 +1,224 @@
+package patchclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoJSONRetriesOn503AndSucceeds(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond})
+
+	_, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetPlantList returned error: %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestDoJSONDoesNotRetryOn501(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond})
+
+	_, err := client.GetPlantList(context.Background(), nil, nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected exactly 1 attempt, got %d", got)
+	}
+}
+
+func TestDoJSONExhaustsRetriesAndSurfacesAttemptCount(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond})
+
+	_, err := client.GetPlantList(context.Background(), nil, nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	patchErr, ok := err.(*PatchClientError)
+	if !ok {
+		t.Fatalf("expected PatchClientError, got %T (%v)", err, err)
+	}
+	if patchErr.Attempts != 3 {
+		t.Fatalf("expected 3 attempts surfaced, got %d", patchErr.Attempts)
+	}
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Fatalf("expected 3 requests sent, got %d", got)
+	}
+}
+
+func TestDoJSONHonorsRetryAfterDeltaSeconds(t *testing.T) {
+	var hits int32
+	var firstAttempt time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			firstAttempt = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond})
+
+	_, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetPlantList returned error: %v", err)
+	}
+	if elapsed := time.Since(firstAttempt); elapsed < time.Second {
+		t.Fatalf("expected retry to wait at least 1s per Retry-After, waited %s", elapsed)
+	}
+}
+
+func TestDoJSONStopsRetryingWhenContextCancelled(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 5, BaseDelay: 50 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetPlantList(ctx, nil, nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if got := atomic.LoadInt32(&hits); got >= 6 {
+		t.Fatalf("expected cancellation to cut retries short, got %d attempts", got)
+	}
+}
+
+func TestParseRetryAfterHandlesHTTPDate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	header := now.Add(5 * time.Second).UTC().Format(http.TimeFormat)
+
+	got, ok := parseRetryAfter(header, now)
+	if !ok {
+		t.Fatal("expected HTTP-date Retry-After to parse")
+	}
+	if got != 5*time.Second {
+		t.Fatalf("unexpected delay: %s", got)
+	}
+}
+
+func TestComputeBackoffCapsAtMaxDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 2 * time.Second}
+	got := computeBackoff(policy, 10, 0, false)
+	if got != 2*time.Second {
+		t.Fatalf("expected backoff capped at MaxDelay, got %s", got)
+	}
+}
+
+func TestDoJSONHonorsCustomRetryOn(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetRetryPolicy(RetryPolicy{
+		MaxRetries: 2,
+		BaseDelay:  time.Millisecond,
+		RetryOn: func(statusCode int, err error) bool {
+			return statusCode == http.StatusNotFound
+		},
+	})
+
+	_, err := client.GetPlantList(context.Background(), nil, nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Fatalf("expected RetryOn to force 3 attempts on a normally-non-retryable status, got %d", got)
+	}
+}
+
+func TestDoJSONSendsStableIdempotencyKeyAcrossRetries(t *testing.T) {
+	var hits int32
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if atomic.AddInt32(&hits, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond})
+
+	_, err := client.CreatePlant(context.Background(), map[string]any{"name": "plant-1"}, nil)
+	if err != nil {
+		t.Fatalf("CreatePlant returned error: %v", err)
+	}
+	if len(keys) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(keys))
+	}
+	for _, k := range keys {
+		if k == "" || k != keys[0] {
+			t.Fatalf("expected the same Idempotency-Key on every attempt, got %v", keys)
+		}
+	}
+}
+
+func TestDoJSONOmitsIdempotencyKeyForBodylessRequests(t *testing.T) {
+	var gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("Idempotency-Key")
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+		t.Fatalf("GetPlantList returned error: %v", err)
+	}
+	if gotKey != "" {
+		t.Fatalf("expected no Idempotency-Key on a bodyless request, got %q", gotKey)
+	}
+}
@@ -0,0 +1,158 @@
+package patchclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TokenSource supplies access tokens for a Client, so long-running programs
+// don't have to manually re-authenticate. Token is called whenever the
+// cached token is empty or within the configured refresh skew of its
+// expiry.
+type TokenSource interface {
+	Token(ctx context.Context) (accessToken string, expiry time.Time, err error)
+}
+
+const defaultTokenRefreshSkew = 30 * time.Second
+
+// tokenRefreshCall is an in-flight Token() call shared by every goroutine
+// that observes a stale cached token at the same time, so concurrent
+// requests trigger a single refresh instead of a thundering herd.
+type tokenRefreshCall struct {
+	done   chan struct{}
+	token  string
+	expiry time.Time
+	err    error
+}
+
+// SetTokenSource installs a TokenSource used to populate the Authorization
+// header automatically. It takes priority over SetAccessToken unless a
+// request's RequestOptions.AccessToken is set explicitly. Pass nil to go
+// back to manually managed tokens.
+func (c *Client) SetTokenSource(source TokenSource) {
+	c.mu.Lock()
+	c.tokenSource = source
+	c.mu.Unlock()
+	c.invalidateToken()
+}
+
+// SetTokenRefreshSkew controls how far ahead of expiry a cached token is
+// considered stale and eagerly refreshed. The default is 30 seconds.
+func (c *Client) SetTokenRefreshSkew(skew time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokenSkew = skew
+}
+
+func (c *Client) getTokenSource() TokenSource {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tokenSource
+}
+
+func (c *Client) getTokenSkew() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.tokenSkew <= 0 {
+		return defaultTokenRefreshSkew
+	}
+	return c.tokenSkew
+}
+
+func (c *Client) invalidateToken() {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	c.cachedToken = ""
+	c.cachedExpiry = time.Time{}
+}
+
+// ensureToken returns a fresh access token, refreshing via source.Token
+// when the cached token is empty or within the refresh skew of expiry.
+func (c *Client) ensureToken(ctx context.Context, source TokenSource) (string, error) {
+	c.tokenMu.Lock()
+	token := c.cachedToken
+	expiry := c.cachedExpiry
+	c.tokenMu.Unlock()
+
+	skew := c.getTokenSkew()
+	if token != "" && (expiry.IsZero() || time.Now().Add(skew).Before(expiry)) {
+		return token, nil
+	}
+	return c.refreshToken(ctx, source)
+}
+
+func (c *Client) refreshToken(ctx context.Context, source TokenSource) (string, error) {
+	skew := c.getTokenSkew()
+	c.tokenMu.Lock()
+	if call := c.refreshing; call != nil {
+		c.tokenMu.Unlock()
+		<-call.done
+		return call.token, call.err
+	}
+	// Another goroutine may have completed a refresh after ensureToken read
+	// the cache; reuse its token instead of starting a second refresh.
+	if token := c.cachedToken; token != "" && (c.cachedExpiry.IsZero() || time.Now().Add(skew).Before(c.cachedExpiry)) {
+		c.tokenMu.Unlock()
+		return token, nil
+	}
+
+	call := &tokenRefreshCall{done: make(chan struct{})}
+	c.refreshing = call
+	c.tokenMu.Unlock()
+
+	token, expiry, err := source.Token(ctx)
+
+	c.tokenMu.Lock()
+	call.token, call.expiry, call.err = token, expiry, err
+	if err == nil {
+		c.cachedToken = token
+		c.cachedExpiry = expiry
+	}
+	c.refreshing = nil
+	c.tokenMu.Unlock()
+	close(call.done)
+
+	return token, err
+}
+
+const defaultPatchAuthTokenLifetime = time.Hour
+
+// PatchAuthTokenSource is a TokenSource that re-authenticates against the
+// PATCH API's auth-with-password endpoint via an existing Client.
+type PatchAuthTokenSource struct {
+	Client      *Client
+	Email       string
+	Password    string
+	AccountType AccountType
+}
+
+func (s *PatchAuthTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	out, err := s.Client.AuthenticateUser(ctx, map[string]any{
+		"type":     string(s.AccountType),
+		"email":    s.Email,
+		"password": s.Password,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	resp, ok := out.(map[string]any)
+	if !ok {
+		return "", time.Time{}, fmt.Errorf("unexpected AuthenticateUser response shape: %T", out)
+	}
+
+	token, _ := resp["token"].(string)
+	if token == "" {
+		return "", time.Time{}, fmt.Errorf("AuthenticateUser response missing token field")
+	}
+
+	expiry := time.Now().Add(defaultPatchAuthTokenLifetime)
+	if raw, ok := resp["expiry"].(string); ok && raw != "" {
+		if parsed, parseErr := time.Parse(time.RFC3339, raw); parseErr == nil {
+			expiry = parsed
+		}
+	}
+
+	return token, expiry, nil
+}
@@ -0,0 +1,137 @@
+package patchclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeTokenSource struct {
+	calls  int32
+	token  string
+	expiry time.Time
+	err    error
+}
+
+func (s *fakeTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	atomic.AddInt32(&s.calls, 1)
+	return s.token, s.expiry, s.err
+}
+
+func TestTokenSourcePopulatesAuthorizationHeader(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetTokenSource(&fakeTokenSource{token: "tok-1", expiry: time.Now().Add(time.Hour)})
+
+	_, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetPlantList returned error: %v", err)
+	}
+	if gotAuth != "Bearer tok-1" {
+		t.Fatalf("unexpected Authorization header: %s", gotAuth)
+	}
+}
+
+func TestTokenSourceIsNotCalledAgainBeforeExpirySkew(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	source := &fakeTokenSource{token: "tok-1", expiry: time.Now().Add(time.Hour)}
+	client := NewClient(srv.URL)
+	client.SetTokenSource(source)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetPlantList(context.Background(), nil, nil); err != nil {
+			t.Fatalf("GetPlantList returned error: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&source.calls); got != 1 {
+		t.Fatalf("expected exactly 1 Token() call, got %d", got)
+	}
+}
+
+func TestTokenSourceRefreshesOnUnauthorizedResponse(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer tok-2" {
+			t.Errorf("expected refreshed token on retry, got %q", r.Header.Get("Authorization"))
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	calls := int32(0)
+	source := &fakeTokenSourceFunc{fn: func() (string, time.Time, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 1 {
+			return "tok-1", time.Now().Add(time.Hour), nil
+		}
+		return "tok-2", time.Now().Add(time.Hour), nil
+	}}
+
+	client := NewClient(srv.URL)
+	client.SetTokenSource(source)
+
+	_, err := client.GetPlantList(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetPlantList returned error: %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Fatalf("expected a single retry after 401, got %d requests", got)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected Token() to be called twice (initial + refresh), got %d", got)
+	}
+}
+
+type fakeTokenSourceFunc struct {
+	fn func() (string, time.Time, error)
+}
+
+func (s *fakeTokenSourceFunc) Token(ctx context.Context) (string, time.Time, error) {
+	return s.fn()
+}
+
+func TestConcurrentRequestsShareOneTokenRefresh(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	source := &fakeTokenSource{token: "tok-1", expiry: time.Now().Add(time.Hour)}
+	client := NewClient(srv.URL)
+	client.SetTokenSource(source)
+
+	done := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		go func() {
+			_, err := client.GetPlantList(context.Background(), nil, nil)
+			done <- err
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		if err := <-done; err != nil {
+			t.Fatalf("GetPlantList returned error: %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&source.calls); got != 1 {
+		t.Fatalf("expected concurrent requests to share a single refresh, got %d calls", got)
+	}
+}
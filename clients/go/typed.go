@@ -0,0 +1,117 @@
+package patchclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"patchclient/models"
+)
+
+// Do issues a request through c exactly like doJSON does, then decodes the
+// response into T instead of any. It re-decodes the already-fetched value
+// rather than bypassing doJSON, so retries, rate limiting, caching, and the
+// token/middleware pipeline behave identically to the untyped methods.
+func Do[T any](ctx context.Context, c *Client, method, path string, query map[string]string, body any, opts *RequestOptions) (T, error) {
+	var zero T
+
+	decoded, err := c.doJSON(ctx, method, path, query, body, nil, opts)
+	if err != nil {
+		return zero, err
+	}
+
+	raw, err := json.Marshal(decoded)
+	if err != nil {
+		return zero, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if c.getDisallowUnknownJSONFields() {
+		dec.DisallowUnknownFields()
+	}
+
+	var out T
+	if err := dec.Decode(&out); err != nil {
+		return zero, err
+	}
+	return out, nil
+}
+
+// SetDisallowUnknownJSONFields controls whether Do (and the *Typed client
+// methods built on it) reject response fields that don't exist on T. It is
+// off by default so new, undocumented API fields don't break typed callers.
+func (c *Client) SetDisallowUnknownJSONFields(disallow bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disallowUnknownJSONFields = disallow
+}
+
+func (c *Client) getDisallowUnknownJSONFields() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.disallowUnknownJSONFields
+}
+
+// plantListEnvelope mirrors the {"items": [...], "cursor": "..."} shape
+// GetPlantList's pagination (see pagination.go's DefaultPlantPageExtractor)
+// already assumes for this endpoint.
+type plantListEnvelope struct {
+	Items  []models.Plant `json:"items"`
+	Cursor string         `json:"cursor"`
+}
+
+// GetPlantListTyped is the typed equivalent of GetPlantList.
+func (c *Client) GetPlantListTyped(ctx context.Context, query map[string]string, opts *RequestOptions) ([]models.Plant, error) {
+	envelope, err := Do[plantListEnvelope](ctx, c, http.MethodGet, "/api/v3/plants", query, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.Items, nil
+}
+
+// GetPlantDetailsTyped is the typed equivalent of GetPlantDetails.
+func (c *Client) GetPlantDetailsTyped(ctx context.Context, plantID string, opts *RequestOptions) (models.Plant, error) {
+	path := fmt.Sprintf("/api/v3/plants/%s", encodePath(plantID))
+	return Do[models.Plant](ctx, c, http.MethodGet, path, nil, nil, opts)
+}
+
+// GetAccountInfoTyped is the typed equivalent of GetAccountInfo.
+func (c *Client) GetAccountInfoTyped(ctx context.Context, opts *RequestOptions) (models.AccountInfo, error) {
+	return Do[models.AccountInfo](ctx, c, http.MethodGet, "/api/v3/account/", nil, nil, opts)
+}
+
+// inverterLogEnvelope mirrors the {"logs": [...], "cursor": "..."} shape
+// ListInverterLogs's pagination (see pagination.go's
+// DefaultInverterLogPageExtractor) already assumes for this endpoint.
+type inverterLogEnvelope struct {
+	Logs   []models.InverterLog `json:"logs"`
+	Cursor string               `json:"cursor"`
+}
+
+// ListInverterLogsTyped is the typed equivalent of ListInverterLogs.
+func (c *Client) ListInverterLogsTyped(ctx context.Context, plantID string, query map[string]string, opts *RequestOptions) ([]models.InverterLog, error) {
+	path := fmt.Sprintf("/api/v3/plants/%s/logs/inverter", encodePath(plantID))
+	envelope, err := Do[inverterLogEnvelope](ctx, c, http.MethodGet, path, query, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.Logs, nil
+}
+
+// ListInverterLogsByIDTyped is the typed equivalent of ListInverterLogsByID.
+func (c *Client) ListInverterLogsByIDTyped(ctx context.Context, plantID string, inverterID string, query map[string]string, opts *RequestOptions) ([]models.InverterLog, error) {
+	path := fmt.Sprintf("/api/v3/plants/%s/logs/inverters/%s", encodePath(plantID), encodePath(inverterID))
+	envelope, err := Do[inverterLogEnvelope](ctx, c, http.MethodGet, path, query, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.Logs, nil
+}
+
+// GetLatestDeviceMetricsTyped is the typed equivalent of GetLatestDeviceMetrics.
+func (c *Client) GetLatestDeviceMetricsTyped(ctx context.Context, plantID string, query map[string]string, opts *RequestOptions) ([]models.DeviceMetric, error) {
+	path := fmt.Sprintf("/api/v3/plants/%s/metrics/device/latest", encodePath(plantID))
+	return Do[[]models.DeviceMetric](ctx, c, http.MethodGet, path, query, nil, opts)
+}
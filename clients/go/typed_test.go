@@ -0,0 +1,148 @@
+package patchclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"patchclient/models"
+)
+
+func TestGetPlantDetailsTypedDecodesIntoModel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"plant-1","name":"Rooftop A","address":"123 Main St","capacity":42.5}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	plant, err := client.GetPlantDetailsTyped(context.Background(), "plant-1", nil)
+	if err != nil {
+		t.Fatalf("GetPlantDetailsTyped returned error: %v", err)
+	}
+	if plant != (models.Plant{ID: "plant-1", Name: "Rooftop A", Address: "123 Main St", Capacity: 42.5}) {
+		t.Fatalf("unexpected plant: %+v", plant)
+	}
+}
+
+func TestGetPlantListTypedDecodesIntoSlice(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[{"id":"plant-1","name":"Rooftop A"},{"id":"plant-2","name":"Rooftop B"}],"cursor":""}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	plants, err := client.GetPlantListTyped(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetPlantListTyped returned error: %v", err)
+	}
+	if len(plants) != 2 || plants[0].ID != "plant-1" || plants[1].ID != "plant-2" {
+		t.Fatalf("unexpected plants: %+v", plants)
+	}
+}
+
+func TestListInverterLogsTypedDecodesIntoSlice(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"logs":[{"inverter_id":"inv-1"},{"inverter_id":"inv-2"}],"cursor":""}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	logs, err := client.ListInverterLogsTyped(context.Background(), "plant-1", nil, nil)
+	if err != nil {
+		t.Fatalf("ListInverterLogsTyped returned error: %v", err)
+	}
+	if len(logs) != 2 || logs[0].InverterID != "inv-1" || logs[1].InverterID != "inv-2" {
+		t.Fatalf("unexpected inverter logs: %+v", logs)
+	}
+}
+
+func TestListInverterLogsByIDTypedDecodesIntoSlice(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"logs":[{"inverter_id":"inv-1"}],"cursor":""}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	logs, err := client.ListInverterLogsByIDTyped(context.Background(), "plant-1", "inv-1", nil, nil)
+	if err != nil {
+		t.Fatalf("ListInverterLogsByIDTyped returned error: %v", err)
+	}
+	if len(logs) != 1 || logs[0].InverterID != "inv-1" {
+		t.Fatalf("unexpected inverter logs: %+v", logs)
+	}
+}
+
+func TestGetPlantListTypedToleratesCursorFieldWithStrictMode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[{"id":"plant-1","name":"Rooftop A"}],"cursor":"abc123"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetDisallowUnknownJSONFields(true)
+
+	plants, err := client.GetPlantListTyped(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetPlantListTyped returned error with strict mode on: %v", err)
+	}
+	if len(plants) != 1 || plants[0].ID != "plant-1" {
+		t.Fatalf("unexpected plants: %+v", plants)
+	}
+}
+
+func TestListInverterLogsTypedToleratesCursorFieldWithStrictMode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"logs":[{"inverter_id":"inv-1"}],"cursor":"abc123"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetDisallowUnknownJSONFields(true)
+
+	logs, err := client.ListInverterLogsTyped(context.Background(), "plant-1", nil, nil)
+	if err != nil {
+		t.Fatalf("ListInverterLogsTyped returned error with strict mode on: %v", err)
+	}
+	if len(logs) != 1 || logs[0].InverterID != "inv-1" {
+		t.Fatalf("unexpected inverter logs: %+v", logs)
+	}
+}
+
+func TestDoRejectsUnknownFieldsWhenConfigured(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"plant-1","name":"Rooftop A","unexpected_field":true}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetDisallowUnknownJSONFields(true)
+
+	if _, err := client.GetPlantDetailsTyped(context.Background(), "plant-1", nil); err == nil {
+		t.Fatal("expected an error decoding an unrecognized field, got nil")
+	}
+}
+
+func TestDoAllowsUnknownFieldsByDefault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"plant-1","name":"Rooftop A","unexpected_field":true}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	plant, err := client.GetPlantDetailsTyped(context.Background(), "plant-1", nil)
+	if err != nil {
+		t.Fatalf("GetPlantDetailsTyped returned error: %v", err)
+	}
+	if plant.ID != "plant-1" {
+		t.Fatalf("unexpected plant: %+v", plant)
+	}
+}
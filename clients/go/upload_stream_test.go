@@ -0,0 +1,118 @@
+package patchclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUploadPlantFilesStreamSendsBodyFromReader(t *testing.T) {
+	var gotName, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+			t.Errorf("unexpected content type: %s", r.Header.Get("Content-Type"))
+		}
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			if part.FileName() == "" {
+				continue
+			}
+			gotName = part.FileName()
+			body, _ := io.ReadAll(part)
+			gotBody = string(body)
+			break
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	content := "streamed via FilePartStream"
+	_, err := client.UploadPlantFilesStream(
+		context.Background(),
+		"plant-1",
+		nil,
+		map[string]FilePartStream{
+			"filename": {
+				Filename:    "stream.txt",
+				ContentType: "text/plain",
+				Body:        strings.NewReader(content),
+				Size:        int64(len(content)),
+			},
+		},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("UploadPlantFilesStream returned error: %v", err)
+	}
+	if gotName != "stream.txt" {
+		t.Fatalf("unexpected filename: %s", gotName)
+	}
+	if gotBody != content {
+		t.Fatalf("unexpected body: %q", gotBody)
+	}
+}
+
+func TestUploadPlantFilesStreamHonorsMaxInFlight(t *testing.T) {
+	var inFlight, maxSeen int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			seen := atomic.LoadInt32(&maxSeen)
+			if n <= seen || atomic.CompareAndSwapInt32(&maxSeen, seen, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	client.SetMaxInFlight(2)
+
+	done := make(chan error, 6)
+	for i := 0; i < 6; i++ {
+		go func() {
+			_, err := client.UploadPlantFilesStream(
+				context.Background(),
+				"plant-1",
+				nil,
+				map[string]FilePartStream{
+					"filename": {
+						Filename:    "stream.txt",
+						ContentType: "text/plain",
+						Body:        strings.NewReader("streamed"),
+						Size:        int64(len("streamed")),
+					},
+				},
+				nil,
+			)
+			done <- err
+		}()
+	}
+	for i := 0; i < 6; i++ {
+		if err := <-done; err != nil {
+			t.Fatalf("UploadPlantFilesStream returned error: %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&maxSeen); got > 2 {
+		t.Fatalf("expected at most 2 concurrent streaming uploads, saw %d", got)
+	}
+}